@@ -0,0 +1,190 @@
+package cas
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math/bits"
+	"os"
+
+	"github.com/hemantthanna/cas/schema"
+	"github.com/hemantthanna/cas/types"
+)
+
+// ChunkerParams controls the content-defined chunking performed by
+// HashFileChunked.
+type ChunkerParams struct {
+	Min uint64
+	Avg uint64
+	Max uint64
+}
+
+// DefaultChunker is used when ChunkOptions.Chunker is the zero value:
+// ~2 MiB average chunks, with a 512 KiB floor and an 8 MiB ceiling.
+var DefaultChunker = ChunkerParams{
+	Min: 512 * 1024,
+	Avg: 2 * 1024 * 1024,
+	Max: 8 * 1024 * 1024,
+}
+
+// ChunkOptions configures HashFileChunked.
+type ChunkOptions struct {
+	Chunker ChunkerParams
+	// Force re-chunks and re-hashes the file even if StatFile already has a
+	// cached ref for it.
+	Force bool
+}
+
+// HashFileChunked streams the file at path through a content-defined
+// chunker (FastCDC-style: a rolling "gear" hash over a sliding window emits
+// a boundary whenever the low bits of the hash are zero, bounded by
+// Min/Avg/Max chunk sizes) and stores each chunk as its own blob. It writes
+// a schema.File describing the chunk list and returns its ref.
+//
+// Unlike HashWith, which stores a file as a single blob, this lets CAS
+// deduplicate large files at chunk granularity: editing one part of a file
+// only changes the chunks that cover that part.
+func HashFileChunked(ctx context.Context, path string, info os.FileInfo, opts ChunkOptions) (SizedRef, error) {
+	if opts.Chunker == (ChunkerParams{}) {
+		opts.Chunker = DefaultChunker
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return SizedRef{}, err
+	}
+	defer f.Close()
+
+	if !opts.Force {
+		if sr, err := StatFile(ctx, f); err == nil && !sr.Ref.Zero() {
+			return sr, nil
+		}
+	}
+
+	br := bufio.NewReaderSize(f, 1<<20)
+
+	var (
+		chunks []schema.Chunk
+		total  uint64
+	)
+	for {
+		buf, err := nextChunk(br, opts.Chunker)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return SizedRef{}, err
+		}
+		sr, err := hashChunk(ctx, buf)
+		if err != nil {
+			return SizedRef{}, err
+		}
+		chunks = append(chunks, schema.Chunk{Ref: sr.Ref, Size: sr.Size, Offset: total})
+		total += sr.Size
+	}
+
+	file := &schema.File{
+		Size:   total,
+		Chunks: chunks,
+		Chunker: schema.Chunker{
+			Algo: "fastcdc",
+			Min:  opts.Chunker.Min,
+			Avg:  opts.Chunker.Avg,
+			Max:  opts.Chunker.Max,
+		},
+	}
+	return StoreSchema(ctx, file)
+}
+
+// hashChunk hashes and stores a single chunk, reusing the same ref-naming
+// and storage path as HashWith.
+func hashChunk(ctx context.Context, data []byte) (SizedRef, error) {
+	return StoreBytes(ctx, data)
+}
+
+// StoreBytes hashes an in-memory blob and stores it the same way HashWith
+// stores a file: through a temp file committed via SaveRefFile.
+func StoreBytes(ctx context.Context, data []byte) (SizedRef, error) {
+	h := types.NewRef().Hash()
+	if _, err := h.Write(data); err != nil {
+		return SizedRef{}, err
+	}
+	ref := types.NewRef().WithHash(h)
+
+	tmp, err := os.CreateTemp("", "cas-chunk-*")
+	if err != nil {
+		return SizedRef{}, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := tmp.Write(data); err != nil {
+		return SizedRef{}, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return SizedRef{}, err
+	}
+	if err := SaveRefFile(ctx, tmp, nil, ref); err != nil {
+		return SizedRef{}, err
+	}
+	return SizedRef{Ref: ref, Size: uint64(len(data))}, nil
+}
+
+// gearTable is the FastCDC "gear" hash table: a pseudo-random 64-bit value
+// per possible input byte, combined into a rolling hash as bytes are read.
+var gearTable = newGearTable()
+
+func newGearTable() [256]uint64 {
+	var t [256]uint64
+	// splitmix64, seeded with a fixed constant so the table (and therefore
+	// chunk boundaries) are stable across runs and processes.
+	x := uint64(0x9e3779b97f4a7c15)
+	for i := range t {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		z = z ^ (z >> 31)
+		t[i] = z
+	}
+	return t
+}
+
+// chunkMask returns the bitmask used to test the rolling hash for a chunk
+// boundary, chosen so that the expected chunk size is avg. bits.Len64
+// reports the bit-width needed to represent avg (e.g. 22 for 2^21), which is
+// one more than the exponent itself, so it's decremented before building
+// the mask.
+func chunkMask(avg uint64) uint64 {
+	n := bits.Len64(avg)
+	if n > 0 {
+		n--
+	}
+	return uint64(1)<<uint(n) - 1
+}
+
+// nextChunk reads the next content-defined chunk from br, honoring p's
+// Min/Avg/Max bounds. It returns io.EOF once br is exhausted.
+func nextChunk(br *bufio.Reader, p ChunkerParams) ([]byte, error) {
+	mask := chunkMask(p.Avg)
+	buf := make([]byte, 0, p.Max)
+	var h uint64
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			if len(buf) == 0 {
+				return nil, io.EOF
+			}
+			return buf, nil
+		} else if err != nil {
+			return nil, err
+		}
+		buf = append(buf, b)
+		h = (h << 1) + gearTable[b]
+		if uint64(len(buf)) < p.Min {
+			continue
+		}
+		if uint64(len(buf)) >= p.Max || h&mask == 0 {
+			return buf, nil
+		}
+	}
+}
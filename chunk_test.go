@@ -0,0 +1,66 @@
+package cas
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestChunkMaskMatchesAverage(t *testing.T) {
+	// For a power-of-two average, the mask should zero exactly log2(avg)
+	// low bits, so h&mask == 0 on average once every avg bytes.
+	cases := []struct {
+		avg  uint64
+		want uint64
+	}{
+		{avg: 1 << 10, want: 1<<10 - 1},
+		{avg: 2 * 1024 * 1024, want: 2*1024*1024 - 1},
+		{avg: 1 << 20, want: 1<<20 - 1},
+	}
+	for _, c := range cases {
+		if got := chunkMask(c.avg); got != c.want {
+			t.Errorf("chunkMask(%d) = %#x, want %#x", c.avg, got, c.want)
+		}
+	}
+}
+
+func TestNextChunkHonorsMinAndMax(t *testing.T) {
+	data := bytes.Repeat([]byte{0x42}, 10*1024)
+	br := bufio.NewReader(bytes.NewReader(data))
+	p := ChunkerParams{Min: 1024, Avg: 2048, Max: 4096}
+
+	var total int
+	var sizes []int
+	for {
+		buf, err := nextChunk(br, p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uint64(len(buf)) > p.Max {
+			t.Fatalf("chunk of size %d exceeds Max %d", len(buf), p.Max)
+		}
+		sizes = append(sizes, len(buf))
+		total += len(buf)
+	}
+	if total != len(data) {
+		t.Fatalf("chunked %d bytes total, want %d", total, len(data))
+	}
+	// Only the final chunk may be shorter than Min (input ran out).
+	for i, n := range sizes[:len(sizes)-1] {
+		if uint64(n) < p.Min {
+			t.Fatalf("chunk %d of size %d is below Min %d", i, n, p.Min)
+		}
+	}
+}
+
+func TestNextChunkEOFOnEmptyInput(t *testing.T) {
+	br := bufio.NewReader(bytes.NewReader(nil))
+	_, err := nextChunk(br, DefaultChunker)
+	if err != io.EOF {
+		t.Fatalf("got err = %v, want io.EOF", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/pflag"
 
 	httpstor "github.com/hemantthanna/cas/storage/http"
+	s3store "github.com/hemantthanna/cas/storage/s3"
 
 	"github.com/hemantthanna/cas"
 	"github.com/hemantthanna/cas/config"
@@ -73,4 +74,45 @@ func init() {
 		}),
 	}
 	cmd.AddCommand(initGCSCmd)
+
+	initS3Cmd := &cobra.Command{
+		Use:     "s3",
+		Aliases: []string{"minio"},
+		Short:   "init a client to CAS on an S3-compatible object store",
+		RunE: casInitCmd(func(ctx context.Context, flags *pflag.FlagSet, args []string) (storage.Config, error) {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("expected an S3 URL, e.g. s3://bucket/prefix")
+			}
+			u, err := url.Parse(args[0])
+			if err != nil {
+				return nil, err
+			}
+			if u.Scheme != "s3" {
+				return nil, fmt.Errorf("expected an s3:// URL")
+			}
+			endpoint, _ := flags.GetString("endpoint")
+			region, _ := flags.GetString("region")
+			accessKey, _ := flags.GetString("access-key")
+			secretKey, _ := flags.GetString("secret-key")
+			pathStyle, _ := flags.GetBool("path-style")
+			sse, _ := flags.GetString("sse")
+			return &s3store.Config{
+				Endpoint:     endpoint,
+				Bucket:       u.Host,
+				Prefix:       strings.Trim(u.Path, "/"),
+				Region:       region,
+				AccessKey:    accessKey,
+				SecretKey:    secretKey,
+				UsePathStyle: pathStyle,
+				SSE:          sse,
+			}, nil
+		}),
+	}
+	initS3Cmd.Flags().String("endpoint", "", "S3-compatible endpoint URL (defaults to AWS)")
+	initS3Cmd.Flags().String("region", "", "bucket region")
+	initS3Cmd.Flags().String("access-key", "", "access key (defaults to the standard AWS credential chain)")
+	initS3Cmd.Flags().String("secret-key", "", "secret key")
+	initS3Cmd.Flags().Bool("path-style", false, "use path-style bucket addressing (required by most non-AWS endpoints)")
+	initS3Cmd.Flags().String("sse", "", "server-side encryption algorithm, e.g. AES256")
+	cmd.AddCommand(initS3Cmd)
 }
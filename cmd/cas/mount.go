@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hemantthanna/cas"
+	casfuse "github.com/hemantthanna/cas/fuse"
+	"github.com/hemantthanna/cas/types"
+)
+
+func init() {
+	var rw bool
+	cmd := &cobra.Command{
+		Use:   "mount <ref> <mountpoint>",
+		Short: "mount a CAS ref as a filesystem",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ref, err := types.ParseRef(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid ref: %v", err)
+			}
+			store, err := cas.Open(cmdCtx)
+			if err != nil {
+				return err
+			}
+			opts := casfuse.Options{RW: rw}
+			if rw {
+				opts.OnCommit = func(newRef types.Ref) {
+					fmt.Fprintf(cmd.OutOrStdout(), "committed new ref: %s\n", newRef)
+				}
+			}
+			srv, err := casfuse.Mount(cmdCtx, store, ref, args[1], opts)
+			if err != nil {
+				return err
+			}
+			srv.Wait()
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&rw, "rw", false,
+		"allow writes to a single mounted file, staged and committed to a new ref "+
+			"(printed to stdout) on fsync/close; directory trees are not rewritten, "+
+			"so remounting the original ref loses any writes made this way")
+	Root.AddCommand(cmd)
+}
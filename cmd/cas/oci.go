@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hemantthanna/cas"
+	"github.com/hemantthanna/cas/types"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "oci",
+		Short: "import and export OCI/Docker container images",
+	}
+	Root.AddCommand(cmd)
+
+	pullCmd := &cobra.Command{
+		Use:   "pull <image>",
+		Short: "pull an OCI image and store it as a schema.OCIManifest",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := cas.Open(cmdCtx)
+			if err != nil {
+				return err
+			}
+			sr, err := s.PullOCI(cmdCtx, args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(sr.Ref)
+			return nil
+		},
+	}
+	cmd.AddCommand(pullCmd)
+
+	pushCmd := &cobra.Command{
+		Use:   "push <ref> <image>",
+		Short: "push a stored schema.OCIManifest as an OCI image",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ref, err := types.ParseRef(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid ref: %v", err)
+			}
+			s, err := cas.Open(cmdCtx)
+			if err != nil {
+				return err
+			}
+			return s.PushOCI(cmdCtx, ref, args[1])
+		},
+	}
+	cmd.AddCommand(pushCmd)
+}
@@ -2,6 +2,7 @@ package cas
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 
 	"github.com/hemantthanna/cas/schema"
@@ -15,6 +16,8 @@ func (s *Storage) indexFileByExt(ctx context.Context, name string) storage.BlobW
 	switch ext {
 	case ".gz":
 		return s.indexGZIP(ctx)
+	case ".zst", ".zstd":
+		return s.indexZstd(ctx)
 	}
 	return nil
 }
@@ -27,6 +30,23 @@ func (s *Storage) storeIndexByExt(ctx context.Context, name string, orig, ref ty
 			Arch: orig, Ref: ref, Algo: "gzip",
 		}
 		return s.StoreSchema(ctx, m)
+	case ".zst", ".zstd":
+		// Unlike .gz, ref here already names the schema.CompressedChunked
+		// frame-table index written by indexZstd's BlobWriter.Result(), not
+		// a directly-fetchable decompressed blob -- wrapping it in a flat
+		// schema.Compressed would bury that frame table behind a ref that
+		// looks fetchable but isn't. Link the archive's ref onto the index
+		// itself instead.
+		obj, err := s.FetchSchema(ctx, ref.Ref)
+		if err != nil {
+			return types.SizedRef{}, fmt.Errorf("fetch zstd-chunked index: %v", err)
+		}
+		cc, ok := obj.(*schema.CompressedChunked)
+		if !ok {
+			return types.SizedRef{}, fmt.Errorf("%s is a %T, not a schema.CompressedChunked", ref.Ref, obj)
+		}
+		cc.Arch = orig
+		return s.StoreSchema(ctx, cc)
 	}
 	return types.SizedRef{}, nil
 }
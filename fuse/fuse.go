@@ -0,0 +1,59 @@
+// Package fuse mounts a CAS ref as a read-only (or copy-on-write) POSIX
+// filesystem. The chunks of a chunked file are fetched only as reads reach
+// them; the schema object behind every entry of a directory is fetched and
+// decoded when that directory's children are added, not per lookup.
+package fuse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	"github.com/hemantthanna/cas/storage"
+	"github.com/hemantthanna/cas/types"
+)
+
+// Options configures Mount.
+type Options struct {
+	// RW allows writes. Writes are staged into a local temp file and, on
+	// close/fsync, committed through the usual ingest path to produce a new
+	// immutable ref; the mounted tree itself is never mutated in place.
+	//
+	// Durability is per-file, not per-tree: fsyncing a mounted file commits
+	// its new contents under a new ref and updates that one node in memory,
+	// but no containing schema.Directory is rebuilt, so the new ref is only
+	// reachable through OnCommit below -- remounting the original root ref,
+	// or restarting the mount, loses track of it. RW is best suited to
+	// mounting and editing a single file ref directly.
+	RW bool
+
+	// OnCommit, if set, is called with the new ref every time staged writes
+	// are committed (on fsync, and again on close), since the mounted tree's
+	// own ref never changes.
+	OnCommit func(types.Ref)
+}
+
+// Mount mounts ref (a directory schema or a chunked schema.File) at
+// mountpoint, resolving child refs against store.
+func Mount(ctx context.Context, store storage.Storage, ref types.Ref, mountpoint string, opts Options) (*fuse.Server, error) {
+	root, err := newNode(ctx, store, ref)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve root %s: %v", ref, err)
+	}
+	root.allowWrite = opts.RW
+	root.onCommit = opts.OnCommit
+
+	srv, err := fs.Mount(mountpoint, root, &fs.Options{
+		MountOptions: fuse.MountOptions{
+			FsName:   "cas",
+			Name:     "cas",
+			ReadOnly: !opts.RW,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("mount %s: %v", mountpoint, err)
+	}
+	return srv, nil
+}
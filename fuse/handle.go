@@ -0,0 +1,177 @@
+package fuse
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	"github.com/hemantthanna/cas"
+	"github.com/hemantthanna/cas/schema"
+)
+
+// fileHandle backs reads (and, in --rw mode, writes) of a single open file
+// node. Reads of a chunked file go through a schema.NewFileReader (seek);
+// reads of a plain blob are served from a one-shot in-memory copy, since
+// storage.Storage only exposes a sequential fetch.
+type fileHandle struct {
+	node *node
+
+	mu   sync.Mutex
+	seek io.ReadSeekCloser
+	rc   io.ReadCloser
+	all  []byte
+
+	// tmp, once non-nil, holds writes staged in --rw mode until Fsync
+	// commits them to a new immutable ref.
+	tmp *os.File
+}
+
+var _ fs.FileReader = (*fileHandle)(nil)
+var _ fs.FileWriter = (*fileHandle)(nil)
+var _ fs.FileFsyncer = (*fileHandle)(nil)
+var _ fs.FileReleaser = (*fileHandle)(nil)
+
+func (fh *fileHandle) Read(ctx context.Context, dest []byte, off int64) (fuse.ReadResult, syscall.Errno) {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+
+	if fh.tmp != nil {
+		n, err := fh.tmp.ReadAt(dest, off)
+		if err != nil && err != io.EOF {
+			return nil, syscall.EIO
+		}
+		return fuse.ReadResultData(dest[:n]), 0
+	}
+	if fh.seek != nil {
+		if _, err := fh.seek.Seek(off, io.SeekStart); err != nil {
+			return nil, syscall.EIO
+		}
+		n, err := io.ReadFull(fh.seek, dest)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, syscall.EIO
+		}
+		return fuse.ReadResultData(dest[:n]), 0
+	}
+	if fh.all == nil {
+		data, err := io.ReadAll(fh.rc)
+		if err != nil {
+			return nil, syscall.EIO
+		}
+		fh.all = data
+	}
+	if off >= int64(len(fh.all)) {
+		return fuse.ReadResultData(nil), 0
+	}
+	end := off + int64(len(dest))
+	if end > int64(len(fh.all)) {
+		end = int64(len(fh.all))
+	}
+	return fuse.ReadResultData(fh.all[off:end]), 0
+}
+
+func (fh *fileHandle) Write(ctx context.Context, data []byte, off int64) (uint32, syscall.Errno) {
+	if !fh.node.allowWrite {
+		return 0, syscall.EROFS
+	}
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+	if fh.tmp == nil {
+		tmp, err := os.CreateTemp("", "cas-fuse-*")
+		if err != nil {
+			return 0, syscall.EIO
+		}
+		if err := fh.preload(tmp); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return 0, syscall.EIO
+		}
+		fh.tmp = tmp
+	}
+	n, err := fh.tmp.WriteAt(data, off)
+	if err != nil {
+		return 0, syscall.EIO
+	}
+	return uint32(n), 0
+}
+
+// preload copies the node's full existing content into tmp before the first
+// write is honored, so that a write to some offset other than 0 (or a write
+// that never reads first) doesn't commit a mostly-empty file. It re-fetches
+// from the store rather than relying on fh.all/fh.seek, since those are
+// populated lazily by Read and may never have been touched -- in particular
+// fh.all is never set for a chunked file at all.
+func (fh *fileHandle) preload(tmp *os.File) error {
+	if fh.node.file != nil {
+		r, err := schema.NewFileReader(fh.node.ctx, fh.node.store, fh.node.ref)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		_, err = io.Copy(tmp, r)
+		return err
+	}
+	rc, err := fh.node.store.FetchBlob(fh.node.ctx, fh.node.ref)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	_, err = io.Copy(tmp, rc)
+	return err
+}
+
+// Fsync commits staged writes through the usual ingest path (HashWith),
+// producing a new immutable ref for this node, and updates that node's ref
+// in memory so later reads in this mount see the new content. It does not
+// rewrite the ref of any containing directory -- RW mounts are durable at
+// single-file granularity only, the new ref is reachable only via
+// node.onCommit (if the caller supplied one) or by reading it back through
+// this same still-mounted node; mounting the resulting file directly by its
+// printed ref is the durable way to keep what was just written.
+func (fh *fileHandle) Fsync(ctx context.Context, flags uint32) syscall.Errno {
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+	if fh.tmp == nil {
+		return 0
+	}
+	info, err := fh.tmp.Stat()
+	if err != nil {
+		return syscall.EIO
+	}
+	sr, err := cas.HashWith(fh.node.ctx, fh.tmp.Name(), info, true)
+	if err != nil {
+		return syscall.EIO
+	}
+	fh.node.mu.Lock()
+	fh.node.ref = sr.Ref
+	fh.node.size = sr.Size
+	onCommit := fh.node.onCommit
+	fh.node.mu.Unlock()
+	if onCommit != nil {
+		onCommit(sr.Ref)
+	}
+	return 0
+}
+
+// Release commits any staged writes (as Fsync does) before discarding the
+// temp file, so closing a written file without an explicit fsync keeps them.
+func (fh *fileHandle) Release(ctx context.Context) syscall.Errno {
+	errno := fh.Fsync(ctx, 0)
+	fh.mu.Lock()
+	defer fh.mu.Unlock()
+	if fh.seek != nil {
+		fh.seek.Close()
+	}
+	if fh.rc != nil {
+		fh.rc.Close()
+	}
+	if fh.tmp != nil {
+		fh.tmp.Close()
+		os.Remove(fh.tmp.Name())
+	}
+	return errno
+}
@@ -0,0 +1,151 @@
+package fuse
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"syscall"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+
+	"github.com/hemantthanna/cas/schema"
+	"github.com/hemantthanna/cas/storage"
+	"github.com/hemantthanna/cas/types"
+)
+
+// node is an fs.Inode backed by a CAS ref; newNode resolves the ref as soon
+// as the node is created.
+type node struct {
+	fs.Inode
+
+	ctx   context.Context
+	store storage.Storage
+
+	allowWrite bool
+	onCommit   func(types.Ref)
+
+	mu       sync.Mutex
+	ref      types.Ref
+	resolved bool
+	dir      *schema.Directory
+	file     *schema.File // non-nil if ref is a chunked schema.File
+	size     uint64       // size of a plain (non-chunked) blob, or of file
+}
+
+func newNode(ctx context.Context, store storage.Storage, ref types.Ref) (*node, error) {
+	n := &node{ctx: ctx, store: store, ref: ref}
+	if err := n.resolve(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// resolve decodes the schema object behind n.ref, classifying the node as a
+// directory, a chunked file, or an opaque blob.
+func (n *node) resolve() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.resolved {
+		return nil
+	}
+	rc, err := n.store.FetchBlob(n.ctx, n.ref)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	r, typ, err := schema.PeekType(rc)
+	if err == schema.ErrNotSchema {
+		sr, statErr := n.store.StatBlob(n.ctx, n.ref)
+		if statErr != nil {
+			return statErr
+		}
+		n.size = sr.Size
+		n.resolved = true
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	obj, err := schema.DecodeJSON(r)
+	if err != nil {
+		return fmt.Errorf("decode %s (%s): %v", n.ref, typ, err)
+	}
+	switch o := obj.(type) {
+	case *schema.Directory:
+		n.dir = o
+	case *schema.File:
+		n.file = o
+		n.size = o.Size
+	default:
+		return fmt.Errorf("unsupported tree node type: %s", typ)
+	}
+	n.resolved = true
+	return nil
+}
+
+var _ fs.NodeOnAdder = (*node)(nil)
+
+// OnAdd populates a directory's children when its inode is added, creating
+// (and so resolving) a node for every entry at once; entries are not
+// resolved one at a time on lookup.
+func (n *node) OnAdd(ctx context.Context) {
+	if n.dir == nil {
+		return
+	}
+	for _, e := range n.dir.Entries {
+		child, err := newNode(n.ctx, n.store, e.Ref)
+		if err != nil {
+			// Leave the entry out rather than failing the whole mount;
+			// Lookup will simply report it as missing.
+			continue
+		}
+		child.allowWrite = n.allowWrite
+		child.onCommit = n.onCommit
+		mode := uint32(syscall.S_IFREG)
+		if child.dir != nil {
+			mode = syscall.S_IFDIR
+		}
+		ch := n.NewPersistentInode(ctx, child, fs.StableAttr{Mode: mode})
+		n.AddChild(e.Name, ch, true)
+	}
+}
+
+var _ fs.NodeGetattrer = (*node)(nil)
+
+func (n *node) Getattr(ctx context.Context, f fs.FileHandle, out *fuse.AttrOut) syscall.Errno {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.dir != nil {
+		out.Mode = syscall.S_IFDIR | 0o755
+		return 0
+	}
+	mode := uint32(0o444)
+	if n.allowWrite {
+		mode = 0o644
+	}
+	out.Mode = syscall.S_IFREG | mode
+	out.Size = n.size
+	return 0
+}
+
+var _ fs.NodeOpener = (*node)(nil)
+
+func (n *node) Open(ctx context.Context, flags uint32) (fs.FileHandle, uint32, syscall.Errno) {
+	if n.dir != nil {
+		return nil, 0, syscall.EISDIR
+	}
+	if n.file != nil {
+		r, err := schema.NewFileReader(n.ctx, n.store, n.ref)
+		if err != nil {
+			return nil, 0, syscall.EIO
+		}
+		return &fileHandle{node: n, seek: r}, fuse.FOPEN_KEEP_CACHE, 0
+	}
+	rc, err := n.store.FetchBlob(n.ctx, n.ref)
+	if err != nil {
+		return nil, 0, syscall.EIO
+	}
+	return &fileHandle{node: n, rc: rc}, fuse.FOPEN_KEEP_CACHE, 0
+}
@@ -0,0 +1,100 @@
+package cas
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/hemantthanna/cas/storage/local"
+	"github.com/hemantthanna/cas/types"
+)
+
+// IngestOptions controls how IngestFile avoids a full data copy when
+// storing a file.
+type IngestOptions struct {
+	// Reflink attempts a zero-copy copy-on-write clone (Linux FICLONE) of
+	// the source file into a staging file before hashing it, instead of
+	// streaming its bytes through the hasher directly.
+	Reflink bool
+	// Hardlink is accepted for API symmetry with Reflink: SaveRefFile
+	// already hardlinks the source into the blob store whenever possible,
+	// so setting it has no additional effect today.
+	Hardlink bool
+}
+
+// IngestFile stores the file at path the same way HashWith does, but when
+// opts.Reflink is set and the source's filesystem supports FICLONE, it
+// clones the file into a staging file in the source's own directory and
+// hashes that clone; the blob directory's filesystem is not checked. Reflinking is a
+// best-effort optimization: any failure to stage or clone the file, not
+// just EOPNOTSUPP/EXDEV, falls back to HashWith's regular copy path.
+func IngestFile(ctx context.Context, path string, info os.FileInfo, opts IngestOptions) (SizedRef, error) {
+	if info == nil {
+		st, err := os.Stat(path)
+		if err != nil {
+			return SizedRef{}, err
+		}
+		info = st
+	}
+
+	if opts.Reflink && local.CloneSupported {
+		sr, ok, err := reflinkIngest(ctx, path, info)
+		if err != nil {
+			log.Println("reflink ingest:", err)
+		} else if ok {
+			return sr, nil
+		}
+	}
+	return HashWith(ctx, path, info, false)
+}
+
+// reflinkIngest clones path into a staging file via FICLONE and hashes the
+// clone in place. ok is false (with a nil error) when the filesystem
+// doesn't support reflinks here, so the caller can fall back transparently.
+func reflinkIngest(ctx context.Context, path string, info os.FileInfo) (SizedRef, bool, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return SizedRef{}, false, err
+	}
+	defer src.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cas-reflink-*")
+	if err != nil {
+		return SizedRef{}, false, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := local.Reflink(tmp, src); err != nil {
+		if reflinkUnsupported(err) {
+			return SizedRef{}, false, nil
+		}
+		return SizedRef{}, false, err
+	}
+
+	h := types.NewRef().Hash()
+	n, err := io.Copy(h, tmp)
+	if err != nil {
+		return SizedRef{}, false, err
+	}
+	ref := types.NewRef().WithHash(h)
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return SizedRef{}, false, err
+	}
+	if err := SaveRefFile(ctx, tmp, info, ref); err != nil {
+		return SizedRef{}, false, err
+	}
+	return SizedRef{Ref: ref, Size: uint64(n)}, true, nil
+}
+
+// reflinkUnsupported reports whether err from local.Reflink means the
+// filesystem simply doesn't support reflinking here (as opposed to some
+// other failure reflinkIngest should propagate).
+func reflinkUnsupported(err error) bool {
+	return errors.Is(err, syscall.EOPNOTSUPP) || errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,33 @@
+package cas
+
+import (
+	"io"
+	"syscall"
+	"testing"
+)
+
+func TestReflinkUnsupported(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"EOPNOTSUPP", syscall.EOPNOTSUPP, true},
+		{"EXDEV", syscall.EXDEV, true},
+		{"wrapped EOPNOTSUPP", &wrapErr{syscall.EOPNOTSUPP}, true},
+		{"unrelated error", io.ErrUnexpectedEOF, false},
+		{"EIO", syscall.EIO, false},
+	}
+	for _, c := range cases {
+		if got := reflinkUnsupported(c.err); got != c.want {
+			t.Errorf("reflinkUnsupported(%s) = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+type wrapErr struct{ err error }
+
+func (w *wrapErr) Error() string { return w.err.Error() }
+func (w *wrapErr) Unwrap() error { return w.err }
+
+var _ error = (*wrapErr)(nil)
@@ -0,0 +1,225 @@
+package cas
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	ociMediaType "github.com/google/go-containerregistry/pkg/v1/types"
+
+	"github.com/hemantthanna/cas/schema"
+	"github.com/hemantthanna/cas/types"
+)
+
+// PullOCI fetches the image (or multi-platform index) named ref (e.g.
+// "registry/repo:tag") from its registry and stores it, writing a
+// schema.OCIManifest for a single-platform image or a schema.OCIIndex
+// referencing one schema.OCIManifest per platform for a multi-arch ref.
+// Layer refs match the registry's own (compressed) digest, and each layer's
+// media type is recorded alongside it, so a later PushOCI round-trips
+// byte-for-byte regardless of whether the layer was gzip- or
+// zstd-compressed; layers are also run through the usual extension-based
+// indexer so .tar.gz/.tar.zst content dedups against archives ingested any
+// other way.
+func (s *Storage) PullOCI(ctx context.Context, ref string) (SizedRef, error) {
+	nref, err := name.ParseReference(ref)
+	if err != nil {
+		return SizedRef{}, fmt.Errorf("parse %s: %v", ref, err)
+	}
+	desc, err := remote.Get(nref, remote.WithContext(ctx))
+	if err != nil {
+		return SizedRef{}, fmt.Errorf("pull %s: %v", ref, err)
+	}
+
+	switch desc.MediaType {
+	case ociMediaType.OCIImageIndex, ociMediaType.DockerManifestList:
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return SizedRef{}, fmt.Errorf("read index %s: %v", ref, err)
+		}
+		return s.storeOCIIndex(ctx, ref, idx)
+	default:
+		img, err := desc.Image()
+		if err != nil {
+			return SizedRef{}, fmt.Errorf("read image %s: %v", ref, err)
+		}
+		return s.storeOCIImage(ctx, ref, img)
+	}
+}
+
+// storeOCIIndex stores every platform manifest referenced by idx and writes
+// a schema.OCIIndex tying them together.
+func (s *Storage) storeOCIIndex(ctx context.Context, ref string, idx v1.ImageIndex) (SizedRef, error) {
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return SizedRef{}, err
+	}
+
+	out := &schema.OCIIndex{MediaType: string(manifest.MediaType)}
+	for _, d := range manifest.Manifests {
+		img, err := idx.Image(d.Digest)
+		if err != nil {
+			return SizedRef{}, fmt.Errorf("manifest %s: %v", d.Digest, err)
+		}
+		sr, err := s.storeOCIImage(ctx, ref, img)
+		if err != nil {
+			return SizedRef{}, fmt.Errorf("manifest %s: %v", d.Digest, err)
+		}
+		var platform string
+		if d.Platform != nil {
+			platform = d.Platform.String()
+		}
+		out.Manifests = append(out.Manifests, schema.OCIIndexEntry{Ref: sr.Ref, Platform: platform})
+	}
+	return s.StoreSchema(ctx, out)
+}
+
+// storeOCIImage stores a single-platform image's config and layers and
+// writes the schema.OCIManifest describing it.
+func (s *Storage) storeOCIImage(ctx context.Context, ref string, img v1.Image) (SizedRef, error) {
+	cfgRaw, err := img.RawConfigFile()
+	if err != nil {
+		return SizedRef{}, fmt.Errorf("read config of %s: %v", ref, err)
+	}
+	cfgSR, err := StoreBytes(ctx, cfgRaw)
+	if err != nil {
+		return SizedRef{}, err
+	}
+
+	mt, err := img.MediaType()
+	if err != nil {
+		return SizedRef{}, err
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return SizedRef{}, err
+	}
+
+	m := &schema.OCIManifest{
+		MediaType: string(mt),
+		Config:    cfgSR.Ref,
+	}
+	for i, l := range layers {
+		ol, err := s.storeOCILayer(ctx, l)
+		if err != nil {
+			return SizedRef{}, fmt.Errorf("store layer %d of %s: %v", i, ref, err)
+		}
+		m.Layers = append(m.Layers, ol)
+	}
+	return s.StoreSchema(ctx, m)
+}
+
+// storeOCILayer stores a single OCI layer's compressed bytes as a blob,
+// keyed the same way the registry itself addresses it, recording its media
+// type so PushOCI can reassemble it as the same compression later.
+func (s *Storage) storeOCILayer(ctx context.Context, l v1.Layer) (schema.OCILayer, error) {
+	rc, err := l.Compressed()
+	if err != nil {
+		return schema.OCILayer{}, err
+	}
+	defer rc.Close()
+	raw, err := io.ReadAll(rc)
+	if err != nil {
+		return schema.OCILayer{}, err
+	}
+
+	mt, err := l.MediaType()
+	if err != nil {
+		return schema.OCILayer{}, err
+	}
+	layerName := ociLayerName(string(mt))
+	// Best-effort: feed the layer through the extension-based indexer too,
+	// purely for the dedup side effect of storing its inner blobs; the
+	// layer ref returned below is always the compressed blob, matching the
+	// registry's own digest.
+	if w := s.indexFileByExt(ctx, layerName); w != nil {
+		_, werr := w.Write(raw)
+		if cerr := w.Close(); werr == nil {
+			werr = cerr
+		}
+		_ = werr // indexing is an optimization; ignore failures here
+	}
+
+	sr, err := StoreBytes(ctx, raw)
+	if err != nil {
+		return schema.OCILayer{}, err
+	}
+	return schema.OCILayer{Ref: sr.Ref, Size: sr.Size, MediaType: string(mt)}, nil
+}
+
+// ociLayerName picks the synthetic filename storeOCILayer feeds to
+// indexFileByExt: a media type mentioning zstd gets a .tar.zst name, and
+// every other media type, an uncompressed tar included, gets .tar.gz.
+func ociLayerName(mediaType string) string {
+	if bytes.Contains([]byte(mediaType), []byte("zstd")) {
+		return "layer.tar.zst"
+	}
+	return "layer.tar.gz"
+}
+
+// PushOCI reassembles the schema.OCIManifest at ref and pushes it to dest
+// (e.g. "registry/repo:tag").
+func (s *Storage) PushOCI(ctx context.Context, ref types.Ref, dest string) error {
+	obj, err := s.FetchSchema(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("load manifest %s: %v", ref, err)
+	}
+	m, ok := obj.(*schema.OCIManifest)
+	if !ok {
+		return fmt.Errorf("%s is a %T, not a schema.OCIManifest", ref, obj)
+	}
+
+	cfgRaw, err := s.readBlob(ctx, m.Config)
+	if err != nil {
+		return fmt.Errorf("read config: %v", err)
+	}
+	cfg, err := v1.ParseConfigFile(bytes.NewReader(cfgRaw))
+	if err != nil {
+		return fmt.Errorf("parse config: %v", err)
+	}
+
+	img := empty.Image
+	for i, l := range m.Layers {
+		raw, err := s.readBlob(ctx, l.Ref)
+		if err != nil {
+			return fmt.Errorf("read layer %d: %v", i, err)
+		}
+		var opts []tarball.LayerOption
+		if l.MediaType != "" {
+			opts = append(opts, tarball.WithMediaType(ociMediaType.MediaType(l.MediaType)))
+		}
+		layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(raw)), nil
+		}, opts...)
+		if err != nil {
+			return fmt.Errorf("layer %d: %v", i, err)
+		}
+		img, err = mutate.AppendLayers(img, layer)
+		if err != nil {
+			return fmt.Errorf("append layer %d: %v", i, err)
+		}
+	}
+	img, err = mutate.ConfigFile(img, cfg)
+	if err != nil {
+		return fmt.Errorf("set config: %v", err)
+	}
+	return crane.Push(img, dest, crane.WithContext(ctx))
+}
+
+// readBlob fetches and fully reads a blob's contents.
+func (s *Storage) readBlob(ctx context.Context, ref types.Ref) ([]byte, error) {
+	rc, err := s.FetchBlob(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,20 @@
+package cas
+
+import "testing"
+
+func TestOCILayerName(t *testing.T) {
+	cases := []struct {
+		mediaType string
+		want      string
+	}{
+		{"application/vnd.oci.image.layer.v1.tar+gzip", "layer.tar.gz"},
+		{"application/vnd.docker.image.rootfs.diff.tar.gzip", "layer.tar.gz"},
+		{"application/vnd.oci.image.layer.v1.tar+zstd", "layer.tar.zst"},
+		{"application/vnd.oci.image.layer.v1.tar", "layer.tar.gz"},
+	}
+	for _, c := range cases {
+		if got := ociLayerName(c.mediaType); got != c.want {
+			t.Errorf("ociLayerName(%q) = %q, want %q", c.mediaType, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package schema
+
+import "github.com/hemantthanna/cas/types"
+
+func init() {
+	registerCAS(&CompressedChunked{})
+}
+
+// ZstdFrame describes one independently-stored zstd frame within a
+// zstd-chunked archive.
+type ZstdFrame struct {
+	UncompressedOffset uint64    `json:"uoff"`
+	CompressedOffset   uint64    `json:"coff"`
+	CompressedLen      uint64    `json:"clen"`
+	Ref                types.Ref `json:"ref"`
+}
+
+// CompressedChunked describes a compressed archive stored as a sequence of
+// independently-decodable frames (as produced by zstd-chunked), each kept
+// as its own blob, plus the offset table needed to seek directly to the
+// frame covering a given uncompressed offset.
+type CompressedChunked struct {
+	Arch types.SizedRef `json:"arch"`
+	Algo string         `json:"algo"`
+	Size uint64         `json:"size"` // total uncompressed size
+
+	Frames []ZstdFrame `json:"frames"`
+}
+
+func (c *CompressedChunked) References() []types.Ref {
+	refs := make([]types.Ref, 0, len(c.Frames))
+	for _, f := range c.Frames {
+		refs = append(refs, f.Ref)
+	}
+	return refs
+}
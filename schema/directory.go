@@ -0,0 +1,27 @@
+package schema
+
+import "github.com/hemantthanna/cas/types"
+
+func init() {
+	registerCAS(&Directory{})
+}
+
+// Entry is a single named child of a Directory.
+type Entry struct {
+	Name string    `json:"name"`
+	Ref  types.Ref `json:"ref"`
+}
+
+// Directory is an ordered list of named child refs. Together with File and
+// other leaf schema objects it forms a tree.
+type Directory struct {
+	Entries []Entry `json:"entries"`
+}
+
+func (d *Directory) References() []types.Ref {
+	refs := make([]types.Ref, 0, len(d.Entries))
+	for _, e := range d.Entries {
+		refs = append(refs, e.Ref)
+	}
+	return refs
+}
@@ -0,0 +1,40 @@
+package schema
+
+import "github.com/hemantthanna/cas/types"
+
+func init() {
+	registerCAS(&File{})
+}
+
+// Chunker describes the content-defined chunking parameters that were used
+// to split a File into Chunks.
+type Chunker struct {
+	Algo string `json:"algo"` // e.g. "fastcdc"
+	Min  uint64 `json:"min"`
+	Avg  uint64 `json:"avg"`
+	Max  uint64 `json:"max"`
+}
+
+// Chunk is a single piece of a chunked File, stored as its own blob.
+type Chunk struct {
+	Ref    types.Ref `json:"ref"`
+	Size   uint64    `json:"size"`
+	Offset uint64    `json:"offset"`
+}
+
+// File describes a large blob that was split into content-defined chunks,
+// each stored as an independent blob so that only the chunks that changed
+// need to be re-stored between versions of the file.
+type File struct {
+	Size    uint64  `json:"size"`
+	Chunks  []Chunk `json:"chunks"`
+	Chunker Chunker `json:"chunker"`
+}
+
+func (f *File) References() []types.Ref {
+	refs := make([]types.Ref, 0, len(f.Chunks))
+	for _, c := range f.Chunks {
+		refs = append(refs, c.Ref)
+	}
+	return refs
+}
@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hemantthanna/cas/storage"
+	"github.com/hemantthanna/cas/types"
+)
+
+// fileReader reassembles a chunked File, fetching chunk blobs from store
+// lazily as reads cross chunk boundaries.
+type fileReader struct {
+	ctx   context.Context
+	store storage.Storage
+	file  *File
+
+	pos int64
+	cur io.ReadCloser
+	idx int // index of the chunk backing cur, or -1 if none is open
+}
+
+// NewFileReader returns a seekable reader over a chunked File, fetching only
+// the chunks that are touched by the requested reads.
+func NewFileReader(ctx context.Context, store storage.Storage, ref types.Ref) (io.ReadSeekCloser, error) {
+	rc, err := store.FetchBlob(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	obj, err := Decode(rc)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode chunked file: %v", err)
+	}
+	f, ok := obj.(*File)
+	if !ok {
+		return nil, fmt.Errorf("expected a File schema, got %T", obj)
+	}
+	return &fileReader{ctx: ctx, store: store, file: f, idx: -1}, nil
+}
+
+func (r *fileReader) chunkAt(off int64) (int, int64) {
+	for i, c := range r.file.Chunks {
+		start := int64(c.Offset)
+		end := start + int64(c.Size)
+		if off >= start && off < end {
+			return i, off - start
+		}
+	}
+	return -1, 0
+}
+
+func (r *fileReader) Read(p []byte) (int, error) {
+	if r.pos >= int64(r.file.Size) {
+		return 0, io.EOF
+	}
+	i, skip := r.chunkAt(r.pos)
+	if i < 0 {
+		return 0, io.EOF
+	}
+	if i != r.idx {
+		if r.cur != nil {
+			r.cur.Close()
+		}
+		rc, err := r.store.FetchBlob(r.ctx, r.file.Chunks[i].Ref)
+		if err != nil {
+			return 0, fmt.Errorf("fetch chunk %d: %v", i, err)
+		}
+		if skip > 0 {
+			if _, err := io.CopyN(io.Discard, rc, skip); err != nil {
+				rc.Close()
+				return 0, err
+			}
+		}
+		r.cur, r.idx = rc, i
+	}
+	n, err := r.cur.Read(p)
+	r.pos += int64(n)
+	if err == io.EOF {
+		r.cur.Close()
+		r.cur, r.idx = nil, -1
+		if r.pos < int64(r.file.Size) {
+			err = nil
+		}
+	}
+	return n, err
+}
+
+func (r *fileReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = int64(r.file.Size) + offset
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if pos < 0 {
+		return 0, fmt.Errorf("negative position")
+	}
+	if pos != r.pos && r.cur != nil {
+		r.cur.Close()
+		r.cur, r.idx = nil, -1
+	}
+	r.pos = pos
+	return r.pos, nil
+}
+
+func (r *fileReader) Close() error {
+	if r.cur != nil {
+		return r.cur.Close()
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hemantthanna/cas/storage"
+	"github.com/hemantthanna/cas/types"
+)
+
+// storeChunk hashes data the same way chunk.go's hashChunk does and stores
+// it in store under the resulting ref, which it returns.
+func storeChunk(t *testing.T, ctx context.Context, store storage.Storage, data []byte) types.Ref {
+	t.Helper()
+	h := types.NewRef().Hash()
+	if _, err := h.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	ref := types.NewRef().WithHash(h)
+	if err := store.StoreBlob(ctx, ref, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+func newTestFileReader(t *testing.T, chunks [][]byte) (storage.Storage, *File, io.ReadSeekCloser) {
+	t.Helper()
+	ctx := context.Background()
+	store := storage.NewInMemory()
+
+	f := &File{Chunker: Chunker{Algo: "fastcdc"}}
+	var off uint64
+	for _, c := range chunks {
+		ref := storeChunk(t, ctx, store, c)
+		f.Chunks = append(f.Chunks, Chunk{Ref: ref, Size: uint64(len(c)), Offset: off})
+		off += uint64(len(c))
+	}
+	f.Size = off
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	fileRef := storeChunk(t, ctx, store, buf.Bytes())
+	r, err := NewFileReader(ctx, store, fileRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, f, r
+}
+
+func TestFileReaderReadAcrossChunkBoundary(t *testing.T) {
+	chunks := [][]byte{
+		bytes.Repeat([]byte("a"), 4),
+		bytes.Repeat([]byte("b"), 4),
+		bytes.Repeat([]byte("c"), 4),
+	}
+	_, _, r := newTestFileReader(t, chunks)
+	defer r.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "aaaabbbbcccc"
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestFileReaderSeekForwardAndBackward(t *testing.T) {
+	chunks := [][]byte{
+		[]byte("0123"),
+		[]byte("4567"),
+		[]byte("89AB"),
+	}
+	_, _, r := newTestFileReader(t, chunks)
+	defer r.Close()
+
+	if _, err := r.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "6789" {
+		t.Fatalf("forward seek: got %q, want %q", buf, "6789")
+	}
+
+	if _, err := r.Seek(1, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "1234" {
+		t.Fatalf("backward seek: got %q, want %q", buf, "1234")
+	}
+
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if end != 12 {
+		t.Fatalf("SeekEnd: got %d, want 12", end)
+	}
+	n, err := r.Read(buf)
+	if err != io.EOF || n != 0 {
+		t.Fatalf("read at EOF: got (%d, %v), want (0, io.EOF)", n, err)
+	}
+}
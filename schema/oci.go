@@ -0,0 +1,58 @@
+package schema
+
+import "github.com/hemantthanna/cas/types"
+
+func init() {
+	registerCAS(&OCIManifest{})
+	registerCAS(&OCIIndex{})
+}
+
+// OCIManifest mirrors a single-platform OCI image manifest: a config blob
+// plus an ordered list of layer blobs, each stored as its own CAS blob.
+type OCIManifest struct {
+	MediaType   string            `json:"mediaType"`
+	Config      types.Ref         `json:"config"`
+	Layers      []OCILayer        `json:"layers"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (m *OCIManifest) References() []types.Ref {
+	refs := make([]types.Ref, 0, len(m.Layers)+1)
+	refs = append(refs, m.Config)
+	for _, l := range m.Layers {
+		refs = append(refs, l.Ref)
+	}
+	return refs
+}
+
+// OCILayer is one compressed layer blob of an OCIManifest. MediaType records
+// the registry's own layer media type (e.g. gzip vs zstd compressed tar) so
+// PushOCI can reassemble the layer as the same type it was pulled as, rather
+// than assuming gzip.
+type OCILayer struct {
+	Ref       types.Ref `json:"ref"`
+	Size      uint64    `json:"size"`
+	MediaType string    `json:"mediaType"`
+}
+
+// OCIIndexEntry is one platform-specific manifest referenced by an OCIIndex.
+type OCIIndexEntry struct {
+	Ref      types.Ref `json:"ref"`
+	Platform string    `json:"platform,omitempty"` // e.g. "linux/amd64"
+}
+
+// OCIIndex mirrors a multi-platform OCI image index, referencing one
+// OCIManifest per platform.
+type OCIIndex struct {
+	MediaType   string            `json:"mediaType"`
+	Manifests   []OCIIndexEntry   `json:"manifests"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (i *OCIIndex) References() []types.Ref {
+	refs := make([]types.Ref, 0, len(i.Manifests))
+	for _, m := range i.Manifests {
+		refs = append(refs, m.Ref)
+	}
+	return refs
+}
@@ -20,12 +20,25 @@ import (
 
 const cloneSupported = true
 
+// CloneSupported reports whether this build supports the zero-copy reflink
+// path below (FICLONE is Linux-only).
+const CloneSupported = cloneSupported
+
 var iocFICLONE = ioctl.IOW(0x94, 9, 4) // from linux/fs.h
 
 func cloneFile(dst, src *os.File) error {
 	return ioctl.Ioctl(dst, iocFICLONE, src.Fd())
 }
 
+// Reflink makes dst a copy-on-write clone of src via FICLONE, avoiding a
+// full data copy when both files live on the same filesystem. It returns
+// the ioctl's error unwrapped, typically syscall.EOPNOTSUPP (filesystem
+// doesn't support reflinks) or syscall.EXDEV (different filesystems);
+// callers should fall back to a regular copy on either.
+func Reflink(dst, src *os.File) error {
+	return cloneFile(dst, src)
+}
+
 func linkFile(dir *os.File, name string, file *os.File) error {
 	err := unix.Linkat(unix.AT_FDCWD, file.Name(), int(dir.Fd()), name, unix.AT_SYMLINK_FOLLOW)
 	if e, ok := err.(syscall.Errno); ok && e == syscall.EEXIST {
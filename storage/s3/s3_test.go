@@ -0,0 +1,31 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/hemantthanna/cas/types"
+)
+
+func TestKeyPrefixHandling(t *testing.T) {
+	ref := types.Ref{}
+	want := "blobs/" + ref.String()
+
+	s := &Storage{}
+	if got := s.key(ref); got != want {
+		t.Errorf("key() with no prefix = %q, want %q", got, want)
+	}
+
+	s.prefix = "myprefix"
+	want = "myprefix/blobs/" + ref.String()
+	if got := s.key(ref); got != want {
+		t.Errorf("key() with prefix = %q, want %q", got, want)
+	}
+
+	// Config.Open trims surrounding slashes before storing the prefix, so
+	// key() itself never needs to handle a leading/trailing "/".
+	s.prefix = "myprefix/nested"
+	want = "myprefix/nested/blobs/" + ref.String()
+	if got := s.key(ref); got != want {
+		t.Errorf("key() with nested prefix = %q, want %q", got, want)
+	}
+}
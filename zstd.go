@@ -0,0 +1,239 @@
+package cas
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/hemantthanna/cas/schema"
+	"github.com/hemantthanna/cas/storage"
+)
+
+var zstdMagic = [4]byte{0x28, 0xB5, 0x2F, 0xFD}
+
+// zstdSkippableMin/Max is the magic number range reserved for skippable
+// frames: 0x184D2A50 ... 0x184D2A5F, stored little-endian on the wire.
+const (
+	zstdSkippableMin = 0x184D2A50
+	zstdSkippableMax = 0x184D2A5F
+)
+
+// indexZstd returns a BlobWriter that scans a zstd stream for frame
+// boundaries as bytes arrive, stores each frame as an independent blob, and
+// on Close writes out a schema.CompressedChunked describing the frame
+// table. This mirrors indexGZIP but keeps the archive addressable at frame
+// granularity instead of as one opaque blob.
+//
+// Random-access offsets can only be computed for frames that carry an
+// explicit Frame_Content_Size (the default for `zstd` compressing a
+// regular file); a frame without one would make every later frame's
+// UncompressedOffset wrong too, so indexing fails outright rather than
+// storing an index with silently incorrect offsets.
+func (s *Storage) indexZstd(ctx context.Context) storage.BlobWriter {
+	return &zstdIndexer{ctx: ctx, s: s}
+}
+
+type zstdIndexer struct {
+	ctx context.Context
+	s   *Storage
+
+	buf    bytes.Buffer
+	frames []schema.ZstdFrame
+	coff   uint64
+	uoff   uint64
+	err    error
+	result SizedRef
+}
+
+func (z *zstdIndexer) Write(p []byte) (int, error) {
+	if z.err != nil {
+		return 0, z.err
+	}
+	n, err := z.buf.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if err := z.drain(); err != nil {
+		z.err = err
+		return n, err
+	}
+	return n, nil
+}
+
+// drain extracts and stores as many complete frames as are fully buffered.
+func (z *zstdIndexer) drain() error {
+	for {
+		flen, usize, ok, err := zstdFrameLen(z.buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		data := make([]byte, flen)
+		copy(data, z.buf.Bytes()[:flen])
+		z.buf.Next(flen)
+
+		sr, err := StoreBytes(z.ctx, data)
+		if err != nil {
+			return fmt.Errorf("store zstd frame: %v", err)
+		}
+		z.frames = append(z.frames, schema.ZstdFrame{
+			UncompressedOffset: z.uoff,
+			CompressedOffset:   z.coff,
+			CompressedLen:      uint64(flen),
+			Ref:                sr.Ref,
+		})
+		z.coff += uint64(flen)
+		z.uoff += usize
+	}
+}
+
+func (z *zstdIndexer) Close() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.buf.Len() != 0 {
+		return fmt.Errorf("indexZstd: %d trailing bytes do not form a complete zstd frame", z.buf.Len())
+	}
+	idx := &schema.CompressedChunked{
+		Algo:   "zstd",
+		Size:   z.uoff,
+		Frames: z.frames,
+	}
+	sr, err := StoreSchema(z.ctx, idx)
+	if err != nil {
+		return fmt.Errorf("store zstd-chunked index: %v", err)
+	}
+	z.result = sr
+	return nil
+}
+
+// Result returns the ref of the schema.CompressedChunked index written on
+// Close, describing the archive at frame granularity.
+func (z *zstdIndexer) Result() SizedRef {
+	return z.result
+}
+
+// zstdFrameLen reports the byte length of the first complete frame in b, and
+// its uncompressed size (0 for a skippable frame; a standard frame with no
+// Frame_Content_Size is an error, see parseZstdFrame). ok is false if b does
+// not yet contain a complete frame.
+func zstdFrameLen(b []byte) (flen int, usize uint64, ok bool, err error) {
+	if len(b) < 4 {
+		return 0, 0, false, nil
+	}
+	magic := binary.LittleEndian.Uint32(b[:4])
+	if magic >= zstdSkippableMin && magic <= zstdSkippableMax {
+		if len(b) < 8 {
+			return 0, 0, false, nil
+		}
+		size := binary.LittleEndian.Uint32(b[4:8])
+		total := 8 + int(size)
+		if len(b) < total {
+			return 0, 0, false, nil
+		}
+		return total, 0, true, nil
+	}
+	if !bytes.Equal(b[:4], zstdMagic[:]) {
+		return 0, 0, false, fmt.Errorf("not a zstd frame (bad magic %08x)", magic)
+	}
+	return parseZstdFrame(b)
+}
+
+// parseZstdFrame walks a standard zstd frame (magic already matched in b)
+// and returns its total length and uncompressed content size. A frame with
+// no Frame_Content_Size at all (non-single-segment, flag 0 -- legal per the
+// spec but not what `zstd` emits for a regular file) makes every later
+// frame's UncompressedOffset unrecoverable without decompressing, so it is
+// reported as an error rather than silently treated as size 0.
+func parseZstdFrame(b []byte) (flen int, usize uint64, ok bool, err error) {
+	if len(b) < 5 {
+		return 0, 0, false, nil
+	}
+	fhd := b[4]
+	singleSegment := fhd&(1<<5) != 0
+	contentSizeFlag := fhd >> 6
+	dictIDFlag := fhd & 0x3
+
+	off := 5
+	if !singleSegment {
+		off++ // Window_Descriptor
+	}
+
+	var dictIDLen int
+	switch dictIDFlag {
+	case 0:
+		dictIDLen = 0
+	case 1:
+		dictIDLen = 1
+	case 2:
+		dictIDLen = 2
+	case 3:
+		dictIDLen = 4
+	}
+	off += dictIDLen
+
+	var fcsLen int
+	switch {
+	case contentSizeFlag == 0 && singleSegment:
+		fcsLen = 1
+	case contentSizeFlag == 0:
+		return 0, 0, false, fmt.Errorf("zstd frame has no Frame_Content_Size; cannot track uncompressed offsets")
+	case contentSizeFlag == 1:
+		fcsLen = 2
+	case contentSizeFlag == 2:
+		fcsLen = 4
+	case contentSizeFlag == 3:
+		fcsLen = 8
+	}
+	if len(b) < off+fcsLen {
+		return 0, 0, false, nil
+	}
+	if fcsLen > 0 {
+		switch fcsLen {
+		case 1:
+			usize = uint64(b[off])
+		case 2:
+			usize = uint64(binary.LittleEndian.Uint16(b[off:])) + 256
+		case 4:
+			usize = uint64(binary.LittleEndian.Uint32(b[off:]))
+		case 8:
+			usize = binary.LittleEndian.Uint64(b[off:])
+		}
+	}
+	off += fcsLen
+
+	checksumFlag := fhd&(1<<2) != 0
+
+	for {
+		if len(b) < off+3 {
+			return 0, 0, false, nil
+		}
+		hdr := uint32(b[off]) | uint32(b[off+1])<<8 | uint32(b[off+2])<<16
+		last := hdr&1 != 0
+		blockType := (hdr >> 1) & 0x3
+		blockSize := int(hdr >> 3)
+		off += 3
+		switch blockType {
+		case 1: // RLE_Block: one byte of content regardless of Block_Size
+			off++
+		default: // Raw_Block, Compressed_Block
+			off += blockSize
+		}
+		if len(b) < off {
+			return 0, 0, false, nil
+		}
+		if last {
+			break
+		}
+	}
+	if checksumFlag {
+		off += 4
+	}
+	if len(b) < off {
+		return 0, 0, false, nil
+	}
+	return off, usize, true, nil
+}
@@ -0,0 +1,174 @@
+package cas
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// buildZstdFrame hand-assembles a minimal valid zstd frame (single segment,
+// one Raw_Block holding content verbatim) per the RFC 8878 frame format.
+// There's no zstd binary available to shell out to in this environment, so
+// this plays the role real `zstd`-produced output would in exercising the
+// header parsing below.
+func buildZstdFrame(content []byte, checksum bool) []byte {
+	var b []byte
+	b = append(b, zstdMagic[:]...)
+
+	const singleSegment = 1 << 5
+	var fhd byte = singleSegment
+	if checksum {
+		fhd |= 1 << 2
+	}
+	// Frame_Content_Size_Flag stays 0 (single segment => 1-byte FCS field).
+	b = append(b, fhd)
+	b = append(b, byte(len(content))) // Frame_Content_Size (1 byte)
+
+	// One Raw_Block (Block_Type == 0), marked Last_Block.
+	blockSize := len(content)
+	hdr := uint32(1) | uint32(0)<<1 | uint32(blockSize)<<3
+	b = append(b, byte(hdr), byte(hdr>>8), byte(hdr>>16))
+	b = append(b, content...)
+
+	if checksum {
+		b = append(b, 0, 0, 0, 0)
+	}
+	return b
+}
+
+func TestParseZstdFrameRawBlock(t *testing.T) {
+	content := []byte("hello, zstd frame")
+	frame := buildZstdFrame(content, false)
+
+	flen, usize, ok, err := parseZstdFrame(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("parseZstdFrame: ok = false on a complete frame")
+	}
+	if flen != len(frame) {
+		t.Errorf("flen = %d, want %d", flen, len(frame))
+	}
+	if usize != uint64(len(content)) {
+		t.Errorf("usize = %d, want %d", usize, len(content))
+	}
+}
+
+func TestParseZstdFrameWithChecksum(t *testing.T) {
+	content := []byte("checksummed content")
+	frame := buildZstdFrame(content, true)
+
+	flen, usize, ok, err := parseZstdFrame(frame)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("parseZstdFrame: ok = false on a complete frame")
+	}
+	if flen != len(frame) {
+		t.Errorf("flen = %d, want %d (checksum not accounted for)", flen, len(frame))
+	}
+	if usize != uint64(len(content)) {
+		t.Errorf("usize = %d, want %d", usize, len(content))
+	}
+}
+
+func TestParseZstdFrameIncomplete(t *testing.T) {
+	frame := buildZstdFrame([]byte("truncated"), false)
+	_, _, ok, err := parseZstdFrame(frame[:len(frame)-2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("parseZstdFrame: ok = true on a truncated frame")
+	}
+}
+
+func TestZstdFrameLenMultipleFrames(t *testing.T) {
+	f1 := buildZstdFrame([]byte("first"), false)
+	f2 := buildZstdFrame([]byte("second frame"), true)
+	buf := append(append([]byte{}, f1...), f2...)
+
+	flen, usize, ok, err := zstdFrameLen(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || flen != len(f1) {
+		t.Fatalf("first frame: flen = %d, ok = %v, want %d, true", flen, ok, len(f1))
+	}
+	if usize != 5 {
+		t.Errorf("first frame usize = %d, want 5", usize)
+	}
+
+	flen2, usize2, ok2, err := zstdFrameLen(buf[flen:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok2 || flen2 != len(f2) {
+		t.Fatalf("second frame: flen = %d, ok = %v, want %d, true", flen2, ok2, len(f2))
+	}
+	if usize2 != 12 {
+		t.Errorf("second frame usize = %d, want 12", usize2)
+	}
+}
+
+func TestZstdFrameLenSkippableFrame(t *testing.T) {
+	var b []byte
+	magic := make([]byte, 4)
+	binary.LittleEndian.PutUint32(magic, zstdSkippableMin)
+	b = append(b, magic...)
+	payload := []byte("skippable metadata")
+	size := make([]byte, 4)
+	binary.LittleEndian.PutUint32(size, uint32(len(payload)))
+	b = append(b, size...)
+	b = append(b, payload...)
+
+	flen, usize, ok, err := zstdFrameLen(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("zstdFrameLen: ok = false on a complete skippable frame")
+	}
+	if flen != len(b) {
+		t.Errorf("flen = %d, want %d", flen, len(b))
+	}
+	if usize != 0 {
+		t.Errorf("usize = %d, want 0 (skippable frames carry no content size)", usize)
+	}
+}
+
+func TestParseZstdFrameNoContentSizeErrors(t *testing.T) {
+	// Not single-segment, Frame_Content_Size_Flag == 0: the spec allows
+	// this (streaming output), but it leaves no way to derive this frame's
+	// uncompressed size, so parsing must fail rather than silently
+	// corrupting every later frame's UncompressedOffset.
+	var b []byte
+	b = append(b, zstdMagic[:]...)
+	b = append(b, 0x00)       // Frame_Header_Descriptor: no flags set
+	b = append(b, 0x00)       // Window_Descriptor
+	content := []byte("no size info")
+	hdr := uint32(1) | uint32(0)<<1 | uint32(len(content))<<3
+	b = append(b, byte(hdr), byte(hdr>>8), byte(hdr>>16))
+	b = append(b, content...)
+
+	_, _, _, err := parseZstdFrame(b)
+	if err == nil {
+		t.Fatal("parseZstdFrame: want error for a frame with no Frame_Content_Size, got nil")
+	}
+}
+
+func TestZstdFrameLenBadMagic(t *testing.T) {
+	_, _, _, err := zstdFrameLen([]byte{0, 0, 0, 0})
+	if err == nil {
+		t.Fatal("zstdFrameLen: want error on bad magic, got nil")
+	}
+}
+
+func TestZstdFrameLenNeedsMoreData(t *testing.T) {
+	flen, usize, ok, err := zstdFrameLen(zstdMagic[:2])
+	if err != nil || ok || flen != 0 || usize != 0 {
+		t.Fatalf("zstdFrameLen on short input = (%d, %d, %v, %v), want (0, 0, false, nil)", flen, usize, ok, err)
+	}
+}